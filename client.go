@@ -0,0 +1,306 @@
+package snpp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Client is a stateful SNPP connection. Unlike SendPage, which dials once
+// per page, a Client keeps its TCP connection open so a caller can send
+// many pages with a single LOGIN by issuing RESE between transactions.
+//
+// A Client is not safe for concurrent use.
+type Client struct {
+	address string
+	port    uint64
+	dialer  Dialer
+	conn    net.Conn
+	br      *bufio.Reader
+
+	// ReadTimeout and WriteTimeout bound each read/write when a call is
+	// made without a context deadline. They default to 30 seconds.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// NewClient returns a Client for the gateway at address:port, dialing
+// with a plain *net.Dialer. The connection isn't opened until Dial is
+// called. Use NewClientConfig for TLS, SOCKS5, or other Dialer options.
+func NewClient(address string, port uint64) *Client {
+	return NewClientConfig(Config{Address: address, Port: port})
+}
+
+// NewClientConfig returns a Client built from cfg. The connection isn't
+// opened until Dial is called.
+func NewClientConfig(cfg Config) *Client {
+	dialer := cfg.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+
+	readTimeout := cfg.ReadTimeout
+	if readTimeout == 0 {
+		readTimeout = 30 * time.Second
+	}
+	writeTimeout := cfg.WriteTimeout
+	if writeTimeout == 0 {
+		writeTimeout = 30 * time.Second
+	}
+
+	return &Client{
+		address:      cfg.Address,
+		port:         cfg.Port,
+		dialer:       dialer,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+}
+
+// Dial connects to the gateway and waits for the 220 ready banner. It is
+// equivalent to DialContext(context.Background()).
+func (c *Client) Dial() error {
+	return c.DialContext(context.Background())
+}
+
+// DialContext connects to the gateway, honoring ctx for both the dial and
+// the initial read of the 220 banner.
+func (c *Client) DialContext(ctx context.Context) error {
+	conn, err := c.dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", c.address, c.port))
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.br = bufio.NewReader(conn)
+
+	resp, err := c.read(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 220 {
+		return ErrFailedConnection
+	}
+	return nil
+}
+
+// Close sends QUIT and tears down the connection. Some gateways close
+// their end of the socket right after writing the 221 line, cutting off
+// before a trailing newline arrives; Close treats that specific case -
+// the 221 was already parsed and the only error is EOF - as success, but
+// any other error (a failed write, a read timeout, a reply truncated
+// before its code even arrived) is reported to the caller.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	defer func() {
+		c.conn.Close()
+		c.conn = nil
+	}()
+
+	resp, err := c.QUIT(context.Background())
+	if err != nil {
+		if resp.Code == 221 && errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	if resp.Code != 221 {
+		return ErrForceQuit
+	}
+	return nil
+}
+
+// do writes the given command and reads back the response, using ctx for
+// both operations.
+func (c *Client) do(ctx context.Context, format string, args ...interface{}) (Response, error) {
+	if err := c.write(ctx, fmt.Sprintf(format, args...)); err != nil {
+		return Response{}, err
+	}
+	return c.read(ctx)
+}
+
+// LOGIN authenticates to the gateway with the Level 2 LOGI command.
+func (c *Client) LOGIN(ctx context.Context, loginid, password string) (Response, error) {
+	return c.do(ctx, "LOGI %s %s\r\n", loginid, password)
+}
+
+// LEVEL negotiates the protocol level (1, 2, or 3) with the gateway.
+func (c *Client) LEVEL(ctx context.Context, level int) (Response, error) {
+	return c.do(ctx, "LEVE %d\r\n", level)
+}
+
+// PAGER sets the pager ID for the message under construction.
+func (c *Client) PAGER(ctx context.Context, id string) (Response, error) {
+	return c.do(ctx, "PAGE %s\r\n", id)
+}
+
+// MESSage sets a single-line message body.
+func (c *Client) MESSage(ctx context.Context, text string) (Response, error) {
+	return c.do(ctx, "MESS %s\r\n", text)
+}
+
+// HOLDUntil asks the gateway to hold delivery until the given time, using
+// the MMDDYYHHMMSS format from RFC 1861 section 5.2.5.
+func (c *Client) HOLDUntil(ctx context.Context, until time.Time) (Response, error) {
+	return c.do(ctx, "HOLD %s\r\n", until.Format("010206150405"))
+}
+
+// ALERTOverride enables or disables the gateway's alert-override handling
+// for the message under construction.
+func (c *Client) ALERTOverride(ctx context.Context, enabled bool) (Response, error) {
+	flag := "N"
+	if enabled {
+		flag = "Y"
+	}
+	return c.do(ctx, "ALER %s\r\n", flag)
+}
+
+// COVERAGE sets the coverage area for the message under construction.
+func (c *Client) COVERAGE(ctx context.Context, area string) (Response, error) {
+	return c.do(ctx, "COVE %s\r\n", area)
+}
+
+// SUBJect sets the message subject.
+func (c *Client) SUBJect(ctx context.Context, subject string) (Response, error) {
+	return c.do(ctx, "SUBJ %s\r\n", subject)
+}
+
+// SEND commits the message under construction to the gateway for delivery.
+func (c *Client) SEND(ctx context.Context) (Response, error) {
+	return c.do(ctx, "SEND\r\n")
+}
+
+// RESEt clears any pager ID, message, and options set so far, leaving the
+// connection and login in place so another message can be built on top of
+// it.
+func (c *Client) RESEt(ctx context.Context) (Response, error) {
+	return c.do(ctx, "RESE\r\n")
+}
+
+// HELP asks the gateway for its help text. Gateways that support it reply
+// with a multi-line response.
+func (c *Client) HELP(ctx context.Context) (Response, error) {
+	return c.do(ctx, "HELP\r\n")
+}
+
+// QUIT tells the gateway the session is over. Most callers should use
+// Close instead, which also tears down the TCP connection.
+func (c *Client) QUIT(ctx context.Context) (Response, error) {
+	return c.do(ctx, "QUIT\r\n")
+}
+
+// read reads one reply off the connection's buffered reader, honoring
+// ctx's deadline if it has one.
+func (c *Client) read(ctx context.Context) (Response, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetReadDeadline(deadline)
+	} else {
+		c.conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+	}
+	return readResponse(c.br)
+}
+
+// write sends msg to the connection, honoring ctx's deadline if it has one.
+func (c *Client) write(ctx context.Context, msg string) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	} else {
+		c.conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	}
+	return writeRaw(c.conn, msg)
+}
+
+// SendPageWithClient sends a single page over a fresh Client connection,
+// threading ctx through the dial and every read/write instead of relying
+// on a fixed 30-second deadline.
+func SendPageWithClient(ctx context.Context, address string, port uint64, number string, message string) error {
+	c := NewClient(address, port)
+	if err := c.DialContext(ctx); err != nil {
+		return err
+	}
+	defer c.Close()
+
+	resp, err := c.PAGER(ctx, number)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedPhone
+	}
+
+	resp, err = c.MESSage(ctx, message)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedMessage
+	}
+
+	resp, err = c.SEND(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrFailedSend
+	}
+
+	return nil
+}
+
+// SendPageConfig sends a single page using cfg, which selects the Dialer
+// (for TLS, SOCKS5, or both) and optional LOGIN/LEVEL negotiation that the
+// four-argument SendPage has no way to express.
+func SendPageConfig(ctx context.Context, cfg Config, number, message string) error {
+	c := NewClientConfig(cfg)
+	if err := c.DialContext(ctx); err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if cfg.LoginID != "" {
+		resp, err := c.LOGIN(ctx, cfg.LoginID, cfg.Password)
+		if err != nil {
+			return err
+		}
+		if resp.Code != 250 {
+			return ErrLoginRejected
+		}
+	}
+
+	if cfg.Level != 0 {
+		if _, err := c.LEVEL(ctx, cfg.Level); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.PAGER(ctx, number)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedPhone
+	}
+
+	resp, err = c.MESSage(ctx, message)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedMessage
+	}
+
+	resp, err = c.SEND(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrFailedSend
+	}
+
+	return nil
+}
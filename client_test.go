@@ -0,0 +1,72 @@
+package snpp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// newPipedClient returns a Client whose connection is the server side of
+// a net.Pipe, plus the client side for the test to script replies on.
+func newPipedClient(t *testing.T) (*Client, net.Conn) {
+	t.Helper()
+
+	server, clientSide := net.Pipe()
+	t.Cleanup(func() { server.Close(); clientSide.Close() })
+
+	c := &Client{conn: server, br: bufio.NewReader(server), ReadTimeout: time.Second, WriteTimeout: time.Second}
+	return c, clientSide
+}
+
+func TestClientCloseSuppressesTruncatedFinalLine(t *testing.T) {
+	c, clientSide := newPipedClient(t)
+
+	go func() {
+		br := bufio.NewReader(clientSide)
+		br.ReadString('\n') // consume "QUIT\r\n"
+		// Write a continuation line's worth of a 221 reply, then cut the
+		// connection before the terminator line ever arrives - the
+		// half-closed-gateway case Close is supposed to tolerate.
+		clientSide.Write([]byte("221-Queued for delivery"))
+		clientSide.Close()
+	}()
+
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+}
+
+func TestClientClosePropagatesTruncatedReply(t *testing.T) {
+	c, clientSide := newPipedClient(t)
+
+	go func() {
+		br := bufio.NewReader(clientSide)
+		br.ReadString('\n') // consume "QUIT\r\n"
+		clientSide.Close()  // no reply at all
+	}()
+
+	err := c.Close()
+	if err == nil {
+		t.Fatal("Close() = nil, want an error for a connection closed before any reply")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Close() = %v, want an error wrapping io.EOF", err)
+	}
+}
+
+func TestClientCloseReturnsErrForceQuit(t *testing.T) {
+	c, clientSide := newPipedClient(t)
+
+	go func() {
+		br := bufio.NewReader(clientSide)
+		br.ReadString('\n') // consume "QUIT\r\n"
+		clientSide.Write([]byte("550 Not today\r\n"))
+	}()
+
+	if err := c.Close(); err != ErrForceQuit {
+		t.Errorf("Close() = %v, want %v", err, ErrForceQuit)
+	}
+}
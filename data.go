@@ -0,0 +1,224 @@
+package snpp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultChunkSize is the most characters, "[i/n] " part-number prefix
+// included, SendLong puts in each page when it falls back to splitting a
+// long message across multiple PAGE+MESS+SEND cycles.
+const defaultChunkSize = 100
+
+// ErrChunkSizeTooSmall is returned by SendLong when cfg.ChunkSize is too
+// small to fit even a single character of message body alongside the
+// "[i/n] " part-number prefix.
+var ErrChunkSizeTooSmall = errors.New("snpp: ChunkSize too small to fit part-number prefix")
+
+// Data sends body as a Level 2 DATA transaction: it issues DATA, waits for
+// the 354 continuation reply, streams body line-by-line with dot-stuffing
+// (a leading '.' on any line is doubled, per RFC 1861 section 5.2.14), and
+// terminates with a lone '.' line. The caller still has to issue SEND
+// afterwards to commit the message. If the gateway doesn't support DATA,
+// the returned Response reflects whatever it said instead of 354 and no
+// body is sent.
+func (c *Client) Data(ctx context.Context, body string) (Response, error) {
+	if err := c.write(ctx, "DATA\r\n"); err != nil {
+		return Response{}, err
+	}
+	resp, err := c.read(ctx)
+	if err != nil {
+		return Response{}, err
+	}
+	if resp.Code != 354 {
+		return resp, nil
+	}
+
+	var buf bytes.Buffer
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(line, ".") {
+			buf.WriteString(".")
+		}
+		buf.WriteString(line)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString(".\r\n")
+
+	if err := c.write(ctx, buf.String()); err != nil {
+		return Response{}, err
+	}
+	return c.read(ctx)
+}
+
+// SendLong sends body to number, picking the right transport for its
+// size: MESS for a short single-line body, DATA for anything multi-line
+// or over cfg.ChunkSize. If the gateway doesn't support Level 2 DATA, it
+// falls back to splitting body across multiple PAGE+MESS+SEND cycles on
+// the same connection - using RESE between them - each prefixed with its
+// part number, e.g. "[1/3] ".
+func SendLong(ctx context.Context, cfg Config, number, body string) error {
+	c := NewClientConfig(cfg)
+	if err := c.DialContext(ctx); err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if cfg.LoginID != "" {
+		resp, err := c.LOGIN(ctx, cfg.LoginID, cfg.Password)
+		if err != nil {
+			return err
+		}
+		if resp.Code != 250 {
+			return ErrLoginRejected
+		}
+	}
+
+	chunkSize := cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	if !strings.Contains(body, "\n") && len(body) <= chunkSize {
+		return sendOnePage(ctx, c, number, body)
+	}
+
+	if _, err := c.LEVEL(ctx, 2); err != nil {
+		return err
+	}
+
+	resp, err := c.PAGER(ctx, number)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedPhone
+	}
+
+	dataResp, err := c.Data(ctx, body)
+	if err != nil {
+		return err
+	}
+	if dataResp.Code == 250 {
+		sendResp, err := c.SEND(ctx)
+		if err != nil {
+			return err
+		}
+		if sendResp.Code != 250 {
+			return ErrFailedSend
+		}
+		return nil
+	}
+
+	// Gateway doesn't support Level 2 DATA - fall back to chunked Level 1
+	// pages on the same connection.
+	if _, err := c.RESEt(ctx); err != nil {
+		return err
+	}
+	return sendChunked(ctx, c, number, body, chunkSize)
+}
+
+func sendOnePage(ctx context.Context, c *Client, number, message string) error {
+	resp, err := c.PAGER(ctx, number)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedPhone
+	}
+
+	resp, err = c.MESSage(ctx, message)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrRejectedMessage
+	}
+
+	resp, err = c.SEND(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.Code != 250 {
+		return ErrFailedSend
+	}
+
+	return nil
+}
+
+// sendChunked splits body across multiple PAGE+MESS+SEND cycles, each
+// prefixed with a "[i/n] " part number. The body width is narrowed to
+// leave room for that prefix, so each chunk plus its prefix still fits
+// within chunkSize - not chunkSize plus however wide the prefix happens
+// to be.
+func sendChunked(ctx context.Context, c *Client, number, body string, chunkSize int) error {
+	bodyWidth, err := chunkBodyWidth(body, chunkSize)
+	if err != nil {
+		return err
+	}
+
+	chunks := chunkMessage(body, bodyWidth)
+	for i, chunk := range chunks {
+		if i > 0 {
+			if _, err := c.RESEt(ctx); err != nil {
+				return err
+			}
+		}
+		prefix := partPrefix(i+1, len(chunks))
+		if err := sendOnePage(ctx, c, number, prefix+chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunkBodyWidth finds how many characters of body can go in each chunk
+// so that the chunk plus its "[i/n] " prefix never exceeds chunkSize. The
+// prefix width depends on the total chunk count, which itself depends on
+// the body width, so this converges on a fixed point: each iteration's
+// chunk count can only grow (as width shrinks), so the reserved prefix
+// width only grows too, and both stop moving within a handful of steps.
+func chunkBodyWidth(body string, chunkSize int) (int, error) {
+	width := chunkSize
+	for i := 0; i < 20; i++ {
+		n := len(chunkMessage(body, width))
+		next := chunkSize - len(partPrefix(n, n))
+		if next <= 0 {
+			return 0, ErrChunkSizeTooSmall
+		}
+		if next == width {
+			return width, nil
+		}
+		width = next
+	}
+	return width, nil
+}
+
+// partPrefix formats the "[i/n] " part-number prefix SendLong adds to
+// each chunk of a split message.
+func partPrefix(i, n int) string {
+	return fmt.Sprintf("[%d/%d] ", i, n)
+}
+
+// chunkMessage collapses body's whitespace (MESS is single-line only) and
+// splits it into pieces of at most chunkSize characters.
+func chunkMessage(body string, chunkSize int) []string {
+	flat := strings.Join(strings.Fields(body), " ")
+	if flat == "" {
+		return []string{""}
+	}
+
+	var chunks []string
+	for len(flat) > 0 {
+		n := chunkSize
+		if n > len(flat) {
+			n = len(flat)
+		}
+		chunks = append(chunks, flat[:n])
+		flat = flat[n:]
+	}
+	return chunks
+}
@@ -0,0 +1,238 @@
+package snpp
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brentbrent/gosnpp/snpptest"
+)
+
+func dialClient(t *testing.T, server *snpptest.Server) *Client {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		t.Fatalf("ParseUint: %v", err)
+	}
+
+	c := NewClient(host, port)
+	if err := c.Dial(); err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestSendChunkedRespectsChunkSize(t *testing.T) {
+	const chunkSize = 12 // small enough that "[1/n] " prefixes matter
+
+	var sentMessages []string
+	server := snpptest.NewServer(snpptest.HandlerFunc(func(cmd string) []string {
+		switch {
+		case strings.HasPrefix(cmd, "PAGE"):
+			return []string{"250 Pager ID accepted"}
+		case strings.HasPrefix(cmd, "MESS"):
+			sentMessages = append(sentMessages, strings.TrimPrefix(cmd, "MESS "))
+			return []string{"250 Message accepted"}
+		case strings.HasPrefix(cmd, "SEND"):
+			return []string{"250 Message sent"}
+		case strings.HasPrefix(cmd, "RESE"):
+			return []string{"250 Reset"}
+		case strings.HasPrefix(cmd, "QUIT"):
+			return []string{"221 Goodbye"}
+		}
+		return []string{"500 Command unrecognized"}
+	}))
+	defer server.Close()
+
+	c := dialClient(t, server)
+
+	body := "this message is long enough that it has to be split into several chunks to fit"
+	if err := sendChunked(context.Background(), c, "5551234567", body, chunkSize); err != nil {
+		t.Fatalf("sendChunked: %v", err)
+	}
+
+	if len(sentMessages) == 0 {
+		t.Fatal("no MESS commands were sent")
+	}
+	for i, msg := range sentMessages {
+		if len(msg) > chunkSize {
+			t.Errorf("chunk %d: %q is %d bytes, want <= %d (ChunkSize)", i, msg, len(msg), chunkSize)
+		}
+	}
+
+	// The body (minus the part-number prefixes) should round-trip intact.
+	var reassembled strings.Builder
+	for i, msg := range sentMessages {
+		prefix := partPrefix(i+1, len(sentMessages))
+		if !strings.HasPrefix(msg, prefix) {
+			t.Fatalf("message %q missing expected prefix %q", msg, prefix)
+		}
+		reassembled.WriteString(strings.TrimPrefix(msg, prefix))
+	}
+	if reassembled.String() != body {
+		t.Errorf("reassembled body = %q, want %q", reassembled.String(), body)
+	}
+}
+
+func TestSendChunkedErrChunkSizeTooSmall(t *testing.T) {
+	server := snpptest.NewServer(snpptest.Script{})
+	defer server.Close()
+
+	c := dialClient(t, server)
+
+	if err := sendChunked(context.Background(), c, "5551234567", "hello world", 1); err != ErrChunkSizeTooSmall {
+		t.Errorf("sendChunked() error = %v, want %v", err, ErrChunkSizeTooSmall)
+	}
+}
+
+func TestClientDataLevel2(t *testing.T) {
+	script := snpptest.Script{
+		"PAGE": {"250 Pager ID accepted"},
+		"DATA": {"354 Start message input"},
+		".":    {"250 Message accepted"},
+		"SEND": {"250 Message sent"},
+	}
+	server := snpptest.NewServer(script)
+	defer server.Close()
+
+	c := dialClient(t, server)
+
+	resp, err := c.Data(context.Background(), "line one\nline two\n.dot-stuffed line")
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	if resp.Code != 250 {
+		t.Fatalf("Data() Code = %d, want 250", resp.Code)
+	}
+
+	transcript := server.Transcript()
+	var gotLines []string
+	inBody := false
+	for _, line := range transcript {
+		if line == "DATA" {
+			inBody = true
+			continue
+		}
+		if inBody {
+			if line == "." {
+				inBody = false
+				continue
+			}
+			gotLines = append(gotLines, line)
+		}
+	}
+	want := []string{"line one", "line two", "..dot-stuffed line"}
+	if len(gotLines) != len(want) {
+		t.Fatalf("body lines = %v, want %v", gotLines, want)
+	}
+	for i := range want {
+		if gotLines[i] != want[i] {
+			t.Errorf("body line %d = %q, want %q", i, gotLines[i], want[i])
+		}
+	}
+}
+
+func TestClientDataRefused(t *testing.T) {
+	script := snpptest.Script{
+		"DATA": {"500 Command not recognized"},
+	}
+	server := snpptest.NewServer(script)
+	defer server.Close()
+
+	c := dialClient(t, server)
+
+	resp, err := c.Data(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	if resp.Code != 500 {
+		t.Errorf("Data() Code = %d, want 500", resp.Code)
+	}
+}
+
+func TestSendLongShortBodyUsesMess(t *testing.T) {
+	var gotMess bool
+	server := snpptest.NewServer(snpptest.HandlerFunc(func(cmd string) []string {
+		switch {
+		case strings.HasPrefix(cmd, "PAGE"):
+			return []string{"250 Pager ID accepted"}
+		case strings.HasPrefix(cmd, "MESS"):
+			gotMess = true
+			return []string{"250 Message accepted"}
+		case strings.HasPrefix(cmd, "SEND"):
+			return []string{"250 Message sent"}
+		case strings.HasPrefix(cmd, "QUIT"):
+			return []string{"221 Goodbye"}
+		}
+		return []string{"500 Command unrecognized"}
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		t.Fatalf("ParseUint: %v", err)
+	}
+
+	cfg := Config{Address: host, Port: port}
+	if err := SendLong(context.Background(), cfg, "5551234567", "short message"); err != nil {
+		t.Fatalf("SendLong: %v", err)
+	}
+	if !gotMess {
+		t.Error("SendLong did not send a MESS for a short single-line body")
+	}
+}
+
+func TestSendLongFallsBackToChunkedWhenLevel2Refused(t *testing.T) {
+	var messCount int
+	server := snpptest.NewServer(snpptest.HandlerFunc(func(cmd string) []string {
+		switch {
+		case strings.HasPrefix(cmd, "LEVE"):
+			return []string{"250 Level set"}
+		case strings.HasPrefix(cmd, "PAGE"):
+			return []string{"250 Pager ID accepted"}
+		case strings.HasPrefix(cmd, "DATA"):
+			return []string{"500 Command not recognized"}
+		case strings.HasPrefix(cmd, "MESS"):
+			messCount++
+			return []string{"250 Message accepted"}
+		case strings.HasPrefix(cmd, "SEND"):
+			return []string{"250 Message sent"}
+		case strings.HasPrefix(cmd, "RESE"):
+			return []string{"250 Reset"}
+		case strings.HasPrefix(cmd, "QUIT"):
+			return []string{"221 Goodbye"}
+		}
+		return []string{"500 Command unrecognized"}
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		t.Fatalf("ParseUint: %v", err)
+	}
+
+	cfg := Config{Address: host, Port: port, ChunkSize: 12}
+	body := "this message is long enough that it has to be split into several chunks to fit"
+	if err := SendLong(context.Background(), cfg, "5551234567", body); err != nil {
+		t.Fatalf("SendLong: %v", err)
+	}
+	if messCount < 2 {
+		t.Errorf("SendLong sent %d MESS commands, want more than one", messCount)
+	}
+}
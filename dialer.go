@@ -0,0 +1,118 @@
+package snpp
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// Dialer abstracts the transport a Client or SendPageConfig uses to reach
+// an SNPP gateway, so callers can compose TLS, SOCKS5, or both without
+// forking the library. *net.Dialer already satisfies this interface.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// TLSDialer wraps another Dialer and upgrades the resulting connection to
+// TLS, for gateways that require a TLS-wrapped connection (commonly on
+// port 7777).
+type TLSDialer struct {
+	// Dialer is the underlying dialer used to reach the gateway. If nil, a
+	// plain *net.Dialer is used.
+	Dialer Dialer
+	Config *tls.Config
+}
+
+func (d *TLSDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, d.Config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// SOCKS5Dialer reaches the gateway through a SOCKS5 proxy, for deployments
+// where the gateway is only reachable through a corporate proxy.
+type SOCKS5Dialer struct {
+	// ProxyAddress is the address of the SOCKS5 proxy, e.g. "proxy:1080".
+	ProxyAddress string
+	Auth         *proxy.Auth
+
+	// Forward is the dialer used to reach the proxy itself. If nil, a
+	// plain *net.Dialer is used.
+	Forward Dialer
+}
+
+func (d *SOCKS5Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	forward := d.Forward
+	if forward == nil {
+		forward = &net.Dialer{}
+	}
+
+	socksDialer, err := proxy.SOCKS5(network, d.ProxyAddress, d.Auth, contextDialerAdapter{forward})
+	if err != nil {
+		return nil, err
+	}
+
+	if cd, ok := socksDialer.(proxy.ContextDialer); ok {
+		return cd.DialContext(ctx, network, address)
+	}
+	return socksDialer.Dial(network, address)
+}
+
+// contextDialerAdapter makes a Dialer satisfy proxy.Dialer, since
+// golang.org/x/net/proxy predates context.Context.
+type contextDialerAdapter struct {
+	dialer Dialer
+}
+
+func (a contextDialerAdapter) Dial(network, address string) (net.Conn, error) {
+	return a.dialer.DialContext(context.Background(), network, address)
+}
+
+// Config collects everything needed to reach and authenticate to an SNPP
+// gateway, for callers who've outgrown SendPage's four positional
+// arguments.
+type Config struct {
+	Address string
+	Port    uint64
+
+	// Dialer controls how the TCP connection is established. If nil, a
+	// plain *net.Dialer is used.
+	Dialer Dialer
+
+	// ReadTimeout and WriteTimeout bound each read/write when a call is
+	// made without a context deadline. They default to 30 seconds.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// LoginID and Password, if set, are sent via LOGIN right after
+	// connecting by SendPageConfig and SendLong. NewClientConfig does not
+	// use them.
+	LoginID  string
+	Password string
+
+	// Level, if non-zero, is negotiated via LEVEL right after LOGIN by
+	// SendPageConfig. SendLong and NewClientConfig do not use it.
+	Level int
+
+	// ChunkSize bounds how many characters, "[i/n] " part-number prefix
+	// included, SendLong puts in each PAGE+MESS+SEND cycle when it falls
+	// back to splitting a long message across multiple pages. It defaults
+	// to 100.
+	ChunkSize int
+}
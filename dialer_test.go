@@ -0,0 +1,218 @@
+package snpp
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/brentbrent/gosnpp/snpptest"
+)
+
+// generateSelfSignedCert builds an in-memory self-signed certificate for
+// "127.0.0.1", good enough for a TestTLSDialerDialContext loopback server.
+func generateSelfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestTLSDialerDialContext(t *testing.T) {
+	cert := generateSelfSignedCert(t)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatalf("tls.Listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("220 snpp over tls\r\n"))
+	}()
+
+	d := &TLSDialer{Config: &tls.Config{InsecureSkipVerify: true}}
+	conn, err := d.DialContext(context.Background(), "tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	defer conn.Close()
+
+	if _, ok := conn.(*tls.Conn); !ok {
+		t.Fatalf("DialContext returned %T, want *tls.Conn", conn)
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("ReadString: %v", err)
+	}
+	if line != "220 snpp over tls\r\n" {
+		t.Errorf("banner = %q, want %q", line, "220 snpp over tls\r\n")
+	}
+}
+
+// socks5TestProxy is a minimal no-auth SOCKS5 proxy, just enough to
+// exercise SOCKS5Dialer against a real CONNECT round trip: it accepts
+// the version/method handshake, parses a CONNECT request, dials the
+// requested address itself, and relays bytes both ways.
+func socks5TestProxy(t *testing.T) net.Listener {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSocks5Conn(conn)
+		}
+	}()
+
+	return ln
+}
+
+func serveSocks5Conn(conn net.Conn) {
+	defer conn.Close()
+
+	br := bufio.NewReader(conn)
+
+	// Greeting: VER NMETHODS METHODS...
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(br, methods); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{0x05, 0x00}); err != nil { // no auth required
+		return
+	}
+
+	// Request: VER CMD RSV ATYP DST.ADDR DST.PORT
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(br, req); err != nil {
+		return
+	}
+
+	var host string
+	switch req[3] {
+	case 0x01: // IPv4
+		addr := make([]byte, 4)
+		if _, err := io.ReadFull(br, addr); err != nil {
+			return
+		}
+		host = net.IP(addr).String()
+	case 0x03: // domain name
+		lenByte := make([]byte, 1)
+		if _, err := io.ReadFull(br, lenByte); err != nil {
+			return
+		}
+		domain := make([]byte, lenByte[0])
+		if _, err := io.ReadFull(br, domain); err != nil {
+			return
+		}
+		host = string(domain)
+	default:
+		return
+	}
+
+	portBytes := make([]byte, 2)
+	if _, err := io.ReadFull(br, portBytes); err != nil {
+		return
+	}
+	port := binary.BigEndian.Uint16(portBytes)
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, itoa(port)))
+	if err != nil {
+		conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0}) // connection refused
+		return
+	}
+	defer target.Close()
+
+	if _, err := conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(target, br); done <- struct{}{} }()
+	go func() { io.Copy(conn, target); done <- struct{}{} }()
+	<-done
+}
+
+func itoa(n uint16) string {
+	if n == 0 {
+		return "0"
+	}
+	var buf [5]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return string(buf[i:])
+}
+
+func TestSOCKS5DialerDialContext(t *testing.T) {
+	target := snpptest.NewServer(snpptest.Script{})
+	defer target.Close()
+
+	proxy := socks5TestProxy(t)
+	defer proxy.Close()
+
+	d := &SOCKS5Dialer{ProxyAddress: proxy.Addr().String()}
+	conn, err := d.DialContext(context.Background(), "tcp", target.Addr())
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("ReadString: %v", err)
+	}
+	if line != "220 snpptest ready\r\n" {
+		t.Errorf("banner = %q, want %q", line, "220 snpptest ready\r\n")
+	}
+}
@@ -2,11 +2,11 @@ package snpp
 
 import (
 	"bufio"
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,43 +16,93 @@ var ErrRejectedPhone = errors.New("SNPP Gateway did not accept pager number")
 var ErrRejectedMessage = errors.New("SNPP Gateway did not accept message")
 var ErrFailedSend = errors.New("SNPP Gateway did not send message")
 var ErrForceQuit = errors.New("SNPP Gateway did not close remote connection")
+var ErrLoginRejected = errors.New("SNPP Gateway did not accept login credentials")
+
+// Response is the parsed form of an SNPP reply: a 3-digit status code
+// (220, 250, 354, 421, 550, 554, ...) plus the text lines the gateway sent
+// with it. Most replies are a single line, but HELP and some Level 2/3
+// replies span several lines, each prefixed with the same code followed by
+// a '-' until the final line, which is prefixed with the code followed by
+// a space.
+type Response struct {
+	Code  int
+	Lines []string
+}
 
-func read(conn net.Conn) (string, error) {
-	// These gateways can be slow - so set a 30 second timeout
-	conn.SetDeadline(time.Now().Add(30 * time.Second))
+// String joins the response's lines back together, mostly useful for
+// logging.
+func (r Response) String() string {
+	return strings.Join(r.Lines, "\n")
+}
+
+// readResponse reads one complete SNPP reply - a single line, or a
+// multi-line reply terminated by a line whose code matches the first
+// line's code - from r. It reads whole CRLF-terminated lines at a time so
+// a reply split across multiple TCP reads is handled correctly.
+func readResponse(r *bufio.Reader) (Response, error) {
+	var resp Response
 
-	var bigBuffer bytes.Buffer
 	for {
-		readBuf := make([]byte, 256)
-		n, readErr := conn.Read(readBuf)
-		if readErr != nil {
-			if readErr != io.EOF {
-				return "", readErr
-			}
-			break
+		line, err := r.ReadString('\n')
+		if line == "" && err != nil {
+			return Response{}, err
 		}
 
-		bigBuffer.Write(readBuf[:n])
+		trimmed := strings.TrimRight(line, "\r\n")
+		if len(trimmed) < 3 {
+			return Response{}, fmt.Errorf("snpp: short response line: %q", trimmed)
+		}
 
-		// Messages end with a Carriage Return and a New Line
-		if readBuf[n-2] == 13 && readBuf[n-1] == 10 {
-			break
+		code, convErr := strconv.Atoi(trimmed[:3])
+		if convErr != nil || code < 100 {
+			return Response{}, fmt.Errorf("snpp: invalid response code: %q", trimmed)
+		}
+		if resp.Code == 0 {
+			resp.Code = code
+		}
+
+		// trimmed[3] is the separator ('-' for a continuation line, ' '
+		// for the terminator); skip past it so Lines don't keep it.
+		text := ""
+		if len(trimmed) > 3 {
+			text = strings.TrimSpace(trimmed[4:])
+		}
+		resp.Lines = append(resp.Lines, text)
+
+		terminator := len(trimmed) == 3 || trimmed[3] != '-'
+		if terminator && code == resp.Code {
+			return resp, nil
+		}
+		if err != nil {
+			// Return what we parsed so far alongside the error, so a
+			// caller that already has the leading code (e.g. Close,
+			// deciding whether a dropped connection after QUIT's 221 is
+			// fatal) can still inspect it.
+			return resp, err
 		}
 	}
+}
 
-	return bigBuffer.String(), nil
+// writeRaw writes msg to w, flushing immediately. It does not touch any
+// deadline - callers that need one should set it before calling writeRaw.
+func writeRaw(w io.Writer, msg string) error {
+	writer := bufio.NewWriter(w)
+	if _, writeErr := writer.WriteString(msg); writeErr != nil {
+		return writeErr
+	}
+	return writer.Flush()
 }
 
 func write(conn net.Conn, msg string) error {
 	// These gateways can be slow - so set a 30 second timeout
 	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	return writeRaw(conn, msg)
+}
 
-	writer := bufio.NewWriter(conn)
-	if _, writeErr := writer.WriteString(msg); writeErr != nil {
-		return writeErr
-	}
-
-	return writer.Flush()
+func readFrom(conn net.Conn, br *bufio.Reader) (Response, error) {
+	// These gateways can be slow - so set a 30 second timeout
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	return readResponse(br)
 }
 
 /*
@@ -76,55 +126,57 @@ func SendPage(address string, port uint64, number string, message string) error
 	}
 	defer conn.Close()
 
-	msg, readErr := read(conn)
+	br := bufio.NewReader(conn)
+
+	resp, readErr := readFrom(conn, br)
 	if readErr != nil {
 		return readErr
 	}
-	if !strings.HasPrefix(msg, "220") {
+	if resp.Code != 220 {
 		return ErrFailedConnection
 	}
 
 	if writeErr := write(conn, fmt.Sprintf("PAGE %s \r\n", number)); writeErr != nil {
 		return writeErr
 	}
-	msg, readErr = read(conn)
+	resp, readErr = readFrom(conn, br)
 	if readErr != nil {
 		return readErr
 	}
-	if !strings.HasPrefix(msg, "250") {
+	if resp.Code != 250 {
 		return ErrRejectedPhone
 	}
 
 	if writeErr := write(conn, fmt.Sprintf("MESS %s \r\n", message)); writeErr != nil {
 		return writeErr
 	}
-	msg, readErr = read(conn)
+	resp, readErr = readFrom(conn, br)
 	if readErr != nil {
 		return readErr
 	}
-	if !strings.HasPrefix(msg, "250") {
+	if resp.Code != 250 {
 		return ErrRejectedMessage
 	}
 
 	if writeErr := write(conn, "SEND \r\n"); writeErr != nil {
 		return writeErr
 	}
-	msg, readErr = read(conn)
+	resp, readErr = readFrom(conn, br)
 	if readErr != nil {
 		return readErr
 	}
-	if !strings.HasPrefix(msg, "250") {
+	if resp.Code != 250 {
 		return ErrFailedSend
 	}
 
 	if writeErr := write(conn, "QUIT \r\n"); writeErr != nil {
 		return writeErr
 	}
-	msg, readErr = read(conn)
+	resp, readErr = readFrom(conn, br)
 	if readErr != nil {
 		return readErr
 	}
-	if !strings.HasPrefix(msg, "221") {
+	if resp.Code != 221 {
 		return ErrForceQuit
 	}
 
@@ -0,0 +1,122 @@
+package snpp
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brentbrent/gosnpp/snpptest"
+)
+
+func TestSendPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  snpptest.Script
+		wantErr error
+	}{
+		{
+			name: "success",
+			script: snpptest.Script{
+				"PAGE": {"250 Pager ID accepted"},
+				"MESS": {"250 Message accepted"},
+				"SEND": {"250 Message sent"},
+				"QUIT": {"221 Goodbye"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "rejected phone",
+			script: snpptest.Script{
+				"PAGE": {"550 Invalid pager ID"},
+			},
+			wantErr: ErrRejectedPhone,
+		},
+		{
+			name: "rejected message",
+			script: snpptest.Script{
+				"PAGE": {"250 Pager ID accepted"},
+				"MESS": {"554 Message rejected"},
+			},
+			wantErr: ErrRejectedMessage,
+		},
+		{
+			name: "failed send",
+			script: snpptest.Script{
+				"PAGE": {"250 Pager ID accepted"},
+				"MESS": {"250 Message accepted"},
+				"SEND": {"421 Service not available"},
+			},
+			wantErr: ErrFailedSend,
+		},
+		{
+			name: "force quit",
+			script: snpptest.Script{
+				"PAGE": {"250 Pager ID accepted"},
+				"MESS": {"250 Message accepted"},
+				"SEND": {"250 Message sent"},
+				"QUIT": {"421 Service not available"},
+			},
+			wantErr: ErrForceQuit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := snpptest.NewServer(tt.script)
+			defer server.Close()
+
+			host, portStr, err := net.SplitHostPort(server.Addr())
+			if err != nil {
+				t.Fatalf("SplitHostPort: %v", err)
+			}
+			port, err := strconv.ParseUint(portStr, 10, 64)
+			if err != nil {
+				t.Fatalf("ParseUint: %v", err)
+			}
+
+			err = SendPage(host, port, "5551234567", "hello")
+			if err != tt.wantErr {
+				t.Errorf("SendPage() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendPageFailedConnection(t *testing.T) {
+	server := snpptest.NewUnstartedServer(snpptest.Script{})
+	server.Banner = "554 No connections allowed"
+	server.Start()
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		t.Fatalf("ParseUint: %v", err)
+	}
+
+	if err := SendPage(host, port, "5551234567", "hello"); err != ErrFailedConnection {
+		t.Errorf("SendPage() error = %v, want %v", err, ErrFailedConnection)
+	}
+}
+
+func FuzzReadResponse(f *testing.F) {
+	f.Add("220 Gateway ready\r\n")
+	f.Add("214-This is line one\r\n214 End of help\r\n")
+	f.Add("550-\r\n550 \r\n")
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		br := bufio.NewReader(strings.NewReader(raw))
+		resp, err := readResponse(br)
+		if err != nil {
+			return
+		}
+		if resp.Code < 100 || resp.Code > 999 {
+			t.Fatalf("Code out of range: %d", resp.Code)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package snpp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// serveResponses writes each of raw to the client side of a net.Pipe,
+// optionally split across several small writes, and returns the server
+// side's bufio.Reader for the test to read from.
+func serveResponses(t *testing.T, raw string, splitWrites bool) *bufio.Reader {
+	t.Helper()
+
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close(); server.Close() })
+
+	go func() {
+		if !splitWrites {
+			client.Write([]byte(raw))
+			return
+		}
+		for i := 0; i < len(raw); i++ {
+			client.Write([]byte{raw[i]})
+		}
+	}()
+
+	return bufio.NewReader(server)
+}
+
+func TestReadResponseSingleLine(t *testing.T) {
+	br := serveResponses(t, "220 Gateway ready\r\n", false)
+
+	resp, err := readResponse(br)
+	if err != nil {
+		t.Fatalf("readResponse: %v", err)
+	}
+	if resp.Code != 220 {
+		t.Errorf("Code = %d, want 220", resp.Code)
+	}
+	if len(resp.Lines) != 1 || resp.Lines[0] != "Gateway ready" {
+		t.Errorf("Lines = %v, want [\"Gateway ready\"]", resp.Lines)
+	}
+}
+
+func TestReadResponseMultiLine(t *testing.T) {
+	raw := "214-This is line one\r\n214-This is line two\r\n214 End of help\r\n"
+	br := serveResponses(t, raw, false)
+
+	resp, err := readResponse(br)
+	if err != nil {
+		t.Fatalf("readResponse: %v", err)
+	}
+	if resp.Code != 214 {
+		t.Errorf("Code = %d, want 214", resp.Code)
+	}
+	want := []string{"This is line one", "This is line two", "End of help"}
+	if len(resp.Lines) != len(want) {
+		t.Fatalf("Lines = %v, want %v", resp.Lines, want)
+	}
+	for i, line := range want {
+		if resp.Lines[i] != line {
+			t.Errorf("Lines[%d] = %q, want %q", i, resp.Lines[i], line)
+		}
+	}
+}
+
+func TestReadResponseSplitAcrossReads(t *testing.T) {
+	raw := "250-Queued\r\n250 Sent, thanks\r\n"
+	br := serveResponses(t, raw, true)
+
+	resp, err := readResponse(br)
+	if err != nil {
+		t.Fatalf("readResponse: %v", err)
+	}
+	if resp.Code != 250 {
+		t.Errorf("Code = %d, want 250", resp.Code)
+	}
+	want := []string{"Queued", "Sent, thanks"}
+	if len(resp.Lines) != len(want) {
+		t.Fatalf("Lines = %v, want %v", resp.Lines, want)
+	}
+	for i, line := range want {
+		if resp.Lines[i] != line {
+			t.Errorf("Lines[%d] = %q, want %q", i, resp.Lines[i], line)
+		}
+	}
+}
+
+func TestReadResponseEmbeddedCRLFInText(t *testing.T) {
+	raw := "250 message body with \"escaped\" \\r\\n sequence, not a real CRLF\r\n"
+	br := serveResponses(t, raw, false)
+
+	resp, err := readResponse(br)
+	if err != nil {
+		t.Fatalf("readResponse: %v", err)
+	}
+	if resp.Code != 250 {
+		t.Errorf("Code = %d, want 250", resp.Code)
+	}
+	if len(resp.Lines) != 1 {
+		t.Fatalf("Lines = %v, want a single line", resp.Lines)
+	}
+}
+
+func TestClientReadParsesBanner(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	go func() {
+		client.Write([]byte("220 Gateway ready\r\n"))
+	}()
+
+	c := &Client{conn: server, br: bufio.NewReader(server), ReadTimeout: time.Second}
+	resp, err := c.read(context.Background())
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if resp.Code != 220 {
+		t.Errorf("Code = %d, want 220", resp.Code)
+	}
+}
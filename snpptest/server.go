@@ -0,0 +1,202 @@
+// Package snpptest provides a mock SNPP gateway for testing code that
+// talks to github.com/brentbrent/gosnpp, without needing a live gateway.
+package snpptest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler decides how the mock server replies to a single command line
+// (CRLF already stripped).
+type Handler interface {
+	Handle(cmd string) []string
+}
+
+// HandlerFunc adapts a plain function to a Handler.
+type HandlerFunc func(cmd string) []string
+
+func (f HandlerFunc) Handle(cmd string) []string { return f(cmd) }
+
+// Script is a Handler that replies based on a command's verb (its first
+// space-separated word), which covers most canned-response test cases.
+// Commands with no matching verb get a 500.
+type Script map[string][]string
+
+func (s Script) Handle(cmd string) []string {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return []string{"500 Command unrecognized"}
+	}
+	if lines, ok := s[fields[0]]; ok {
+		return lines
+	}
+	return []string{"500 Command unrecognized"}
+}
+
+// Server is a minimal SNPP gateway that scripts its replies through a
+// Handler, for use in tests. Like net/http/httptest.Server, it panics on
+// construction if it can't listen - on loopback, that's unexpected enough
+// to be a bug rather than a condition callers should handle.
+type Server struct {
+	// Banner is sent to the client as soon as it connects. Defaults to
+	// "220 snpptest ready".
+	Banner string
+
+	// SlowWrite, if non-zero, delays every byte written to the client by
+	// this much, to simulate a slow gateway.
+	SlowWrite time.Duration
+
+	// SplitWrites, if true, writes each reply one byte at a time instead
+	// of in one call, to simulate a reply arriving split across reads.
+	SplitWrites bool
+
+	// Truncate, if true, omits the trailing CRLF from every reply and
+	// closes the connection right after writing it, to simulate a
+	// gateway that cuts the connection mid-response. The Banner is
+	// always sent whole.
+	Truncate bool
+
+	ln      net.Listener
+	handler Handler
+
+	mu         sync.Mutex
+	transcript []string
+}
+
+// NewServer creates a Server, starts it listening on a loopback port, and
+// begins serving in the background. Callers must call Close when done.
+func NewServer(handler Handler) *Server {
+	s := NewUnstartedServer(handler)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer creates a Server listening on a loopback port but
+// does not start serving connections yet, so callers can set Banner,
+// SlowWrite, SplitWrites, or Truncate before the first connection arrives.
+// Call Start once configured.
+func NewUnstartedServer(handler Handler) *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	return &Server{
+		Banner:  "220 snpptest ready",
+		ln:      ln,
+		handler: handler,
+	}
+}
+
+// Start begins serving connections in the background.
+func (s *Server) Start() {
+	go s.serve()
+}
+
+// Addr returns the address the server is listening on, e.g. "127.0.0.1:54321".
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server from accepting new connections.
+func (s *Server) Close() error {
+	return s.ln.Close()
+}
+
+// Transcript returns every command line the server has received so far,
+// in order.
+func (s *Server) Transcript() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.transcript))
+	copy(out, s.transcript)
+	return out
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	if !s.writeLine(conn, s.Banner, false) {
+		return
+	}
+
+	br := bufio.NewReader(conn)
+	inDataBody := false
+	for {
+		line, err := br.ReadString('\n')
+		if line == "" && err != nil {
+			return
+		}
+
+		cmd := strings.TrimRight(line, "\r\n")
+		s.mu.Lock()
+		s.transcript = append(s.transcript, cmd)
+		s.mu.Unlock()
+
+		// Inside a Level 2 DATA body, only the dot-terminator line gets a
+		// reply - the body lines in between are recorded but otherwise
+		// silent, just like a real gateway.
+		if inDataBody && cmd != "." {
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		replies := s.handler.Handle(cmd)
+		for _, reply := range replies {
+			if !s.writeLine(conn, reply, s.Truncate) {
+				return
+			}
+		}
+
+		if inDataBody {
+			inDataBody = false
+		} else if strings.HasPrefix(cmd, "DATA") && len(replies) > 0 && strings.HasPrefix(replies[0], "354") {
+			inDataBody = true
+		}
+
+		if err != nil || (!inDataBody && strings.HasPrefix(cmd, "QUIT")) {
+			return
+		}
+	}
+}
+
+// writeLine sends line plus a CRLF to conn, honoring SlowWrite,
+// SplitWrites, and truncate. It reports whether the caller should keep
+// serving this connection.
+func (s *Server) writeLine(conn net.Conn, line string, truncate bool) bool {
+	raw := line + "\r\n"
+	if truncate {
+		raw = line
+	}
+
+	if s.SplitWrites || s.SlowWrite > 0 {
+		for i := 0; i < len(raw); i++ {
+			if s.SlowWrite > 0 {
+				time.Sleep(s.SlowWrite)
+			}
+			if _, err := conn.Write([]byte{raw[i]}); err != nil {
+				return false
+			}
+		}
+	} else if _, err := conn.Write([]byte(raw)); err != nil {
+		return false
+	}
+
+	return !truncate
+}
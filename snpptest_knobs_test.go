@@ -0,0 +1,87 @@
+package snpp
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brentbrent/gosnpp/snpptest"
+)
+
+// dialAddr splits a snpptest.Server's address into the (host, port) pair
+// SendPage's four-argument signature wants.
+func dialAddr(t *testing.T, addr string) (string, uint64) {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 64)
+	if err != nil {
+		t.Fatalf("ParseUint: %v", err)
+	}
+	return host, port
+}
+
+func TestSendPageSplitWrites(t *testing.T) {
+	server := snpptest.NewUnstartedServer(snpptest.Script{
+		"PAGE": {"250 Pager ID accepted"},
+		"MESS": {"250 Message accepted"},
+		"SEND": {"250 Message sent"},
+		"QUIT": {"221 Goodbye"},
+	})
+	server.SplitWrites = true
+	server.Start()
+	defer server.Close()
+
+	host, port := dialAddr(t, server.Addr())
+	if err := SendPage(host, port, "5551234567", "hello"); err != nil {
+		t.Fatalf("SendPage() with replies split across byte-sized writes: %v", err)
+	}
+}
+
+func TestSendPageSlowWrite(t *testing.T) {
+	server := snpptest.NewUnstartedServer(snpptest.Script{
+		"PAGE": {"250 Pager ID accepted"},
+		"MESS": {"250 Message accepted"},
+		"SEND": {"250 Message sent"},
+		"QUIT": {"221 Goodbye"},
+	})
+	server.SlowWrite = 2 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	host, port := dialAddr(t, server.Addr())
+
+	start := time.Now()
+	if err := SendPage(host, port, "5551234567", "hello"); err != nil {
+		t.Fatalf("SendPage() against a slow server: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SendPage() took %v against a slow server, want well under its 30s deadline", elapsed)
+	}
+}
+
+func TestSendPageTruncatedReply(t *testing.T) {
+	// PAGE replies with two lines, simulating a Level 2/3 multi-line
+	// reply; with Truncate set, the server only gets the first
+	// (continuation) line out before closing the connection, so the
+	// client never sees the terminator line it's waiting for.
+	server := snpptest.NewUnstartedServer(snpptest.HandlerFunc(func(cmd string) []string {
+		if strings.HasPrefix(cmd, "PAGE") {
+			return []string{"250-Pager ID accepted", "250 Ready to receive MESS"}
+		}
+		return []string{"500 Command unrecognized"}
+	}))
+	server.Truncate = true
+	server.Start()
+	defer server.Close()
+
+	host, port := dialAddr(t, server.Addr())
+	if err := SendPage(host, port, "5551234567", "hello"); err == nil {
+		t.Fatal("SendPage() = nil, want an error from the truncated multi-line reply")
+	}
+}